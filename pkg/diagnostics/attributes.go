@@ -0,0 +1,111 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// maxAttributeValueLen caps how many bytes of a single leaf value are kept, so one
+	// oversized field can't blow past an exporter's attribute-length limit.
+	maxAttributeValueLen = 256
+	// truncationMarker is appended to a value that was cut off at maxAttributeValueLen.
+	truncationMarker = "...(truncated)"
+	// maxWalkAttributes caps how many leaf attributes an attributeBudget allows in total across
+	// every walkAttributes call that shares it, so a deeply nested or very wide event body can't
+	// blow past an exporter's attribute-count limit either. A single synthetic attribute marks
+	// the cutoff.
+	maxWalkAttributes = 32
+)
+
+// attributeBudget caps the number of leaf attributes still allowed across a series of
+// walkAttributes calls, e.g. one per Event.State entry plus one for Data, so the cap applies to
+// the whole event rather than resetting on every call.
+type attributeBudget struct {
+	remaining int
+	truncated bool
+}
+
+// newAttributeBudget returns a budget allowing up to maxWalkAttributes leaves in total.
+func newAttributeBudget() *attributeBudget {
+	return &attributeBudget{remaining: maxWalkAttributes}
+}
+
+// walkAttributes flattens an arbitrary JSON-like value (as produced by encoding/json: maps,
+// slices, strings, bools, float64/json.Number, or nil) into one Attribute per leaf, keyed by
+// its dotted path from prefix, instead of collapsing the whole value into a single stringified
+// blob. It stops once budget is exhausted, appending a truncation marker the first time that
+// happens.
+func walkAttributes(prefix string, value interface{}, budget *attributeBudget) []Attribute {
+	var attrs []Attribute
+	walk(prefix, value, &attrs, budget)
+	return attrs
+}
+
+func walk(path string, value interface{}, attrs *[]Attribute, budget *attributeBudget) {
+	if budget.remaining <= 0 {
+		markTruncated(path, attrs, budget)
+		return
+	}
+	switch v := value.(type) {
+	case nil:
+		return
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if budget.remaining <= 0 {
+				markTruncated(path, attrs, budget)
+				return
+			}
+			walk(path+"."+k, v[k], attrs, budget)
+		}
+	case []interface{}:
+		for i, item := range v {
+			if budget.remaining <= 0 {
+				markTruncated(path, attrs, budget)
+				return
+			}
+			walk(fmt.Sprintf("%s[%d]", path, i), item, attrs, budget)
+		}
+	case string:
+		*attrs = append(*attrs, StringAttribute(path, truncate(v)))
+		budget.remaining--
+	case bool:
+		*attrs = append(*attrs, BoolAttribute(path, v))
+		budget.remaining--
+	case int64:
+		*attrs = append(*attrs, Int64Attribute(path, v))
+		budget.remaining--
+	case int:
+		*attrs = append(*attrs, Int64Attribute(path, int64(v)))
+		budget.remaining--
+	case float64:
+		*attrs = append(*attrs, Float64Attribute(path, v))
+		budget.remaining--
+	default:
+		*attrs = append(*attrs, StringAttribute(path, truncate(fmt.Sprintf("%v", v))))
+		budget.remaining--
+	}
+}
+
+// markTruncated appends a single synthetic ".truncated" attribute the first time budget is
+// exhausted; later calls sharing the same budget stay silent instead of repeating the marker.
+func markTruncated(path string, attrs *[]Attribute, budget *attributeBudget) {
+	if budget.truncated {
+		return
+	}
+	budget.truncated = true
+	*attrs = append(*attrs, BoolAttribute(path+".truncated", true))
+}
+
+// truncate caps s at maxAttributeValueLen bytes, marking that it was cut off.
+func truncate(s string) string {
+	if len(s) <= maxAttributeValueLen {
+		return s
+	}
+	return s[:maxAttributeValueLen] + truncationMarker
+}
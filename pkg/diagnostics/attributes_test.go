@@ -0,0 +1,88 @@
+package diagnostics
+
+import (
+	"strings"
+	"testing"
+)
+
+func attrMap(attrs []Attribute) map[string]interface{} {
+	m := make(map[string]interface{}, len(attrs))
+	for _, a := range attrs {
+		m[a.Key] = a.Value
+	}
+	return m
+}
+
+func TestWalkAttributes_FlattensNestedValue(t *testing.T) {
+	value := map[string]interface{}{
+		"name":  "order",
+		"count": float64(3),
+		"tags":  []interface{}{"a", "b"},
+	}
+	got := attrMap(walkAttributes("data", value, newAttributeBudget()))
+
+	want := map[string]interface{}{
+		"data.name":    "order",
+		"data.count":   float64(3),
+		"data.tags[0]": "a",
+		"data.tags[1]": "b",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("attribute %q = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestWalkAttributes_TruncatesLongStrings(t *testing.T) {
+	long := strings.Repeat("x", maxAttributeValueLen+10)
+	got := attrMap(walkAttributes("data", long, newAttributeBudget()))
+	s, _ := got["data"].(string)
+	if !strings.HasSuffix(s, truncationMarker) {
+		t.Errorf("value not truncated: len=%d, want suffix %q", len(s), truncationMarker)
+	}
+	if len(s) != maxAttributeValueLen+len(truncationMarker) {
+		t.Errorf("truncated length = %d, want %d", len(s), maxAttributeValueLen+len(truncationMarker))
+	}
+}
+
+func TestWalkAttributes_SharedBudgetAcrossCalls(t *testing.T) {
+	wide := map[string]interface{}{}
+	for i := 0; i < maxWalkAttributes; i++ {
+		wide[string(rune('a'+i%26))+string(rune(i))] = i
+	}
+
+	budget := newAttributeBudget()
+	first := walkAttributes("state.a", wide, budget)
+	second := walkAttributes("state.b", wide, budget)
+
+	total := len(first) + len(second)
+	// One synthetic ".truncated" attribute is allowed on top of the cap; the cap itself must
+	// hold across both calls, not reset per call (maxWalkAttributes*2 would mean it reset).
+	if total > maxWalkAttributes+1 {
+		t.Errorf("total attributes across two walkAttributes calls sharing one budget = %d, want <= %d", total, maxWalkAttributes+1)
+	}
+}
+
+func TestWalkAttributes_TruncationMarkerOnlyOnce(t *testing.T) {
+	wide := map[string]interface{}{}
+	for i := 0; i < maxWalkAttributes*2; i++ {
+		wide[string(rune('a'+i%26))+string(rune(i))] = i
+	}
+
+	budget := newAttributeBudget()
+	first := walkAttributes("state.a", wide, budget)
+	second := walkAttributes("state.b", wide, budget)
+
+	markers := 0
+	for _, attrs := range [][]Attribute{first, second} {
+		for _, a := range attrs {
+			if strings.HasSuffix(a.Key, ".truncated") {
+				markers++
+			}
+		}
+	}
+	if markers != 1 {
+		t.Errorf("truncation markers across both calls = %d, want exactly 1", markers)
+	}
+}
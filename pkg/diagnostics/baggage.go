@@ -0,0 +1,97 @@
+package diagnostics
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// baggageHeader is the W3C baggage header, also used as the wire format for Dapr's dynamic
+// sampling context (release, environment, user-id, tenant, transaction name, ...).
+const baggageHeader = "baggage"
+
+type baggageContextKey struct{}
+
+// Baggage is a set of user-defined key/value pairs that rides alongside a trace's span context.
+// WithBaggage adds or overwrites entries at any hop; this package does not freeze the set after
+// the first hop.
+type Baggage map[string]string
+
+// WithBaggage returns a copy of ctx with key=value added to its Baggage. Existing entries for
+// other keys are preserved; ctx itself is left untouched.
+func WithBaggage(ctx context.Context, key, value string) context.Context {
+	existing := BaggageFromContext(ctx)
+	next := make(Baggage, len(existing)+1)
+	for k, v := range existing {
+		next[k] = v
+	}
+	next[key] = value
+	return context.WithValue(ctx, baggageContextKey{}, next)
+}
+
+// BaggageFromContext returns the Baggage carried by ctx, or an empty Baggage if none was set.
+func BaggageFromContext(ctx context.Context) Baggage {
+	if b, ok := ctx.Value(baggageContextKey{}).(Baggage); ok {
+		return b
+	}
+	return Baggage{}
+}
+
+// ExtractBaggage parses the `baggage` header out of carrier, following the W3C baggage format
+// of comma-separated `key=value` members (any per-member properties after a `;` are ignored).
+func ExtractBaggage(carrier HeaderCarrier) Baggage {
+	header := carrier.Get(baggageHeader)
+	if header == "" {
+		return Baggage{}
+	}
+	baggage := Baggage{}
+	for _, member := range strings.Split(header, ",") {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+		member = strings.SplitN(member, ";", 2)[0]
+		kv := strings.SplitN(member, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(kv[0])
+		value := strings.TrimSpace(kv[1])
+		if key == "" {
+			continue
+		}
+		baggage[key] = value
+	}
+	return baggage
+}
+
+// InjectBaggage writes baggage into carrier's `baggage` header in a deterministic (key-sorted)
+// order so the same Baggage always serializes to the same header value.
+func InjectBaggage(baggage Baggage, carrier HeaderCarrier) {
+	if len(baggage) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(baggage))
+	for k := range baggage {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	members := make([]string, 0, len(keys))
+	for _, k := range keys {
+		members = append(members, k+"="+baggage[k])
+	}
+	carrier.Set(baggageHeader, strings.Join(members, ","))
+}
+
+// baggageAttributes converts a Baggage into span attributes so it shows up on every child span
+// created from ctx without the application having to re-add it per call.
+func baggageAttributes(baggage Baggage) []Attribute {
+	if len(baggage) == 0 {
+		return nil
+	}
+	attrs := make([]Attribute, 0, len(baggage))
+	for k, v := range baggage {
+		attrs = append(attrs, StringAttribute("baggage."+k, v))
+	}
+	return attrs
+}
@@ -0,0 +1,63 @@
+package diagnostics
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithBaggage_AccumulatesWithoutMutatingParent(t *testing.T) {
+	base := context.Background()
+	withRelease := WithBaggage(base, "release", "1.0")
+	withBoth := WithBaggage(withRelease, "tenant", "acme")
+
+	if got := BaggageFromContext(base); len(got) != 0 {
+		t.Errorf("BaggageFromContext(base) = %v, want empty", got)
+	}
+	if got := BaggageFromContext(withRelease); len(got) != 1 || got["release"] != "1.0" {
+		t.Errorf("BaggageFromContext(withRelease) = %v, want {release: 1.0}", got)
+	}
+	want := Baggage{"release": "1.0", "tenant": "acme"}
+	got := BaggageFromContext(withBoth)
+	if len(got) != len(want) || got["release"] != want["release"] || got["tenant"] != want["tenant"] {
+		t.Errorf("BaggageFromContext(withBoth) = %v, want %v", got, want)
+	}
+}
+
+func TestBaggage_HeaderRoundTrip(t *testing.T) {
+	baggage := Baggage{"release": "1.0", "tenant": "acme", "user-id": "42"}
+	carrier := MapCarrier{}
+	InjectBaggage(baggage, carrier)
+
+	got := ExtractBaggage(carrier)
+	if len(got) != len(baggage) {
+		t.Fatalf("ExtractBaggage() = %v, want %v", got, baggage)
+	}
+	for k, v := range baggage {
+		if got[k] != v {
+			t.Errorf("ExtractBaggage()[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestExtractBaggage_IgnoresMemberProperties(t *testing.T) {
+	carrier := MapCarrier{baggageHeader: "release=1.0;label=deploy, tenant=acme"}
+	got := ExtractBaggage(carrier)
+	if got["release"] != "1.0" || got["tenant"] != "acme" {
+		t.Errorf("ExtractBaggage() = %v, want release=1.0, tenant=acme", got)
+	}
+}
+
+func TestInjectBaggage_Deterministic(t *testing.T) {
+	baggage := Baggage{"b": "2", "a": "1"}
+	carrier := MapCarrier{}
+	InjectBaggage(baggage, carrier)
+	if want := "a=1,b=2"; carrier.Get(baggageHeader) != want {
+		t.Errorf("InjectBaggage() header = %q, want %q (key-sorted)", carrier.Get(baggageHeader), want)
+	}
+}
+
+func TestBaggageAttributes_EmptyForNoBaggage(t *testing.T) {
+	if attrs := baggageAttributes(Baggage{}); attrs != nil {
+		t.Errorf("baggageAttributes(empty) = %v, want nil", attrs)
+	}
+}
@@ -0,0 +1,251 @@
+package diagnostics
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	routing "github.com/qiangxue/fasthttp-routing"
+)
+
+const (
+	// traceparentHeader is the W3C Trace Context header carrying version, trace-id, parent-id and flags.
+	traceparentHeader = "traceparent"
+	// tracestateHeader is the W3C Trace Context vendor-specific state header.
+	tracestateHeader = "tracestate"
+
+	// b3SingleHeader is the Zipkin B3 single-header format.
+	b3SingleHeader = "b3"
+	// b3TraceIDHeader, b3SpanIDHeader, b3SampledHeader and b3FlagsHeader make up the B3 multi-header format.
+	b3TraceIDHeader = "X-B3-TraceId"
+	b3SpanIDHeader  = "X-B3-SpanID"
+	b3SampledHeader = "X-B3-Sampled"
+	b3FlagsHeader   = "X-B3-Flags"
+)
+
+// HeaderCarrier abstracts the header storage a Propagator reads from and writes to, so the
+// same Propagator implementation works for fasthttp requests, gRPC metadata or plain maps.
+type HeaderCarrier interface {
+	Get(key string) string
+	Set(key, value string)
+}
+
+// MapCarrier is a HeaderCarrier backed by a plain map, useful for gRPC metadata and tests.
+type MapCarrier map[string]string
+
+// Get returns the header value for key, or "" if it isn't set.
+func (m MapCarrier) Get(key string) string {
+	return m[key]
+}
+
+// Set stores value under key.
+func (m MapCarrier) Set(key, value string) {
+	m[key] = value
+}
+
+// Propagator extracts and injects a SpanContext using a particular wire format.
+type Propagator interface {
+	// Extract reads a span context from carrier. ok is false if carrier did not contain one
+	// in this propagator's format.
+	Extract(carrier HeaderCarrier) (sc SpanContext, ok bool)
+	// Inject writes sc into carrier using this propagator's format.
+	Inject(sc SpanContext, carrier HeaderCarrier)
+}
+
+// legacyPropagator reads and writes the original Dapr "correlation-id" header, kept for
+// compatibility with sidecars that haven't upgraded yet.
+type legacyPropagator struct{}
+
+func (legacyPropagator) Extract(carrier HeaderCarrier) (SpanContext, bool) {
+	corID := carrier.Get(CorrelationID)
+	if corID == "" || len(strings.Split(corID, ";")) != 3 {
+		return SpanContext{}, false
+	}
+	return DeserializeSpanContext(corID), true
+}
+
+func (legacyPropagator) Inject(sc SpanContext, carrier HeaderCarrier) {
+	carrier.Set(CorrelationID, SerializeSpanContext(sc))
+}
+
+// w3cPropagator implements the W3C Trace Context (`traceparent`/`tracestate`) propagation format.
+type w3cPropagator struct{}
+
+func (w3cPropagator) Extract(carrier HeaderCarrier) (SpanContext, bool) {
+	header := carrier.Get(traceparentHeader)
+	parts := strings.Split(header, "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return SpanContext{}, false
+	}
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != 16 {
+		return SpanContext{}, false
+	}
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != 8 {
+		return SpanContext{}, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return SpanContext{}, false
+	}
+	sc := SpanContext{}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	if flags[0]&1 == 1 {
+		sc.TraceOptions = uint32(1)
+	}
+	sc.TraceState = carrier.Get(tracestateHeader)
+	return sc, true
+}
+
+func (w3cPropagator) Inject(sc SpanContext, carrier HeaderCarrier) {
+	flags := "00"
+	if sc.IsSampled() {
+		flags = "01"
+	}
+	carrier.Set(traceparentHeader, fmt.Sprintf("00-%s-%s-%s", hex.EncodeToString(sc.TraceID[:]), hex.EncodeToString(sc.SpanID[:]), flags))
+	if sc.TraceState != "" {
+		carrier.Set(tracestateHeader, sc.TraceState)
+	}
+}
+
+// b3Propagator implements the Zipkin B3 propagation format, reading either the single `b3`
+// header or the multi `X-B3-*` headers and always writing the multi-header form.
+type b3Propagator struct{}
+
+func (b3Propagator) Extract(carrier HeaderCarrier) (SpanContext, bool) {
+	if single := carrier.Get(b3SingleHeader); single != "" {
+		return parseB3Single(single)
+	}
+	traceIDHex := carrier.Get(b3TraceIDHeader)
+	spanIDHex := carrier.Get(b3SpanIDHeader)
+	if traceIDHex == "" || spanIDHex == "" {
+		return SpanContext{}, false
+	}
+	// B3 allows a 64-bit trace-id; left-pad it to the 128-bit OpenCensus representation.
+	if len(traceIDHex) == 16 {
+		traceIDHex = strings.Repeat("0", 16) + traceIDHex
+	}
+	traceID, err := hex.DecodeString(traceIDHex)
+	if err != nil || len(traceID) != 16 {
+		return SpanContext{}, false
+	}
+	spanID, err := hex.DecodeString(spanIDHex)
+	if err != nil || len(spanID) != 8 {
+		return SpanContext{}, false
+	}
+	sc := SpanContext{}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	if carrier.Get(b3SampledHeader) == "1" || carrier.Get(b3FlagsHeader) == "1" {
+		sc.TraceOptions = uint32(1)
+	}
+	return sc, true
+}
+
+// parseB3Single parses the single-header B3 form: `{trace-id}-{span-id}-{sampled}-{parent-span-id}`.
+func parseB3Single(header string) (SpanContext, bool) {
+	if header == "0" {
+		return SpanContext{}, false
+	}
+	parts := strings.Split(header, "-")
+	if len(parts) < 2 {
+		return SpanContext{}, false
+	}
+	traceIDHex := parts[0]
+	if len(traceIDHex) == 16 {
+		traceIDHex = strings.Repeat("0", 16) + traceIDHex
+	}
+	traceID, err := hex.DecodeString(traceIDHex)
+	if err != nil || len(traceID) != 16 {
+		return SpanContext{}, false
+	}
+	spanID, err := hex.DecodeString(parts[1])
+	if err != nil || len(spanID) != 8 {
+		return SpanContext{}, false
+	}
+	sc := SpanContext{}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	if len(parts) >= 3 && (parts[2] == "1" || parts[2] == "d") {
+		sc.TraceOptions = uint32(1)
+	}
+	return sc, true
+}
+
+func (b3Propagator) Inject(sc SpanContext, carrier HeaderCarrier) {
+	carrier.Set(b3TraceIDHeader, hex.EncodeToString(sc.TraceID[:]))
+	carrier.Set(b3SpanIDHeader, hex.EncodeToString(sc.SpanID[:]))
+	if sc.IsSampled() {
+		carrier.Set(b3SampledHeader, "1")
+	} else {
+		carrier.Set(b3SampledHeader, "0")
+	}
+}
+
+// compositePropagator extracts using the first propagator that recognizes the carrier's
+// headers, and injects using all of them so downstream consumers can pick whichever format
+// they understand.
+type compositePropagator struct {
+	propagators []Propagator
+}
+
+// NewCompositePropagator returns a Propagator that tries each of propagators in order when
+// extracting, and injects using all of them.
+func NewCompositePropagator(propagators ...Propagator) Propagator {
+	return &compositePropagator{propagators: propagators}
+}
+
+func (c *compositePropagator) Extract(carrier HeaderCarrier) (SpanContext, bool) {
+	for _, p := range c.propagators {
+		if sc, ok := p.Extract(carrier); ok {
+			return sc, true
+		}
+	}
+	return SpanContext{}, false
+}
+
+func (c *compositePropagator) Inject(sc SpanContext, carrier HeaderCarrier) {
+	for _, p := range c.propagators {
+		p.Inject(sc, carrier)
+	}
+}
+
+// defaultPropagator is used by TraceSpanFromRoutingContext and TraceSpanFromCorrelationId and
+// tries W3C, then B3, then the legacy Dapr correlation-id format.
+var defaultPropagator Propagator = NewCompositePropagator(w3cPropagator{}, b3Propagator{}, legacyPropagator{})
+
+// routingContextCarrier adapts a fasthttp-routing context's request headers to a
+// HeaderCarrier so Propagator implementations can be shared between HTTP and gRPC entry points.
+type routingContextCarrier struct {
+	c *routing.Context
+}
+
+func (r routingContextCarrier) Get(key string) string {
+	return string(r.c.Request.Header.Peek(key))
+}
+
+func (r routingContextCarrier) Set(key, value string) {
+	r.c.Request.Header.Set(key, value)
+}
+
+// metadataCarrier adapts a gRPC metadata map (as returned by metadata.MD) to a HeaderCarrier.
+type metadataCarrier map[string][]string
+
+func (m metadataCarrier) Get(key string) string {
+	if vals, ok := m[strings.ToLower(key)]; ok && len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+func (m metadataCarrier) Set(key, value string) {
+	m[strings.ToLower(key)] = []string{value}
+}
+
+// NewMetadataCarrier wraps gRPC metadata (a map of lower-cased header names to values) as a
+// HeaderCarrier so Propagator implementations can extract/inject trace context on gRPC calls.
+func NewMetadataCarrier(md map[string][]string) HeaderCarrier {
+	return metadataCarrier(md)
+}
@@ -0,0 +1,135 @@
+package diagnostics
+
+import "testing"
+
+func TestW3CPropagator_RoundTrip(t *testing.T) {
+	sc := SpanContext{TraceOptions: 1, TraceState: "vendor=value"}
+	for i := range sc.TraceID {
+		sc.TraceID[i] = byte(i + 1)
+	}
+	for i := range sc.SpanID {
+		sc.SpanID[i] = byte(i + 1)
+	}
+
+	carrier := MapCarrier{}
+	w3cPropagator{}.Inject(sc, carrier)
+
+	got, ok := w3cPropagator{}.Extract(carrier)
+	if !ok {
+		t.Fatalf("Extract() ok = false, want true")
+	}
+	if got != sc {
+		t.Errorf("Extract() = %+v, want %+v", got, sc)
+	}
+}
+
+func TestW3CPropagator_Extract_Invalid(t *testing.T) {
+	for name, carrier := range map[string]MapCarrier{
+		"missing header": {},
+		"wrong version":  {traceparentHeader: "01-0102030405060708090a0b0c0d0e0f10-0102030405060708-00"},
+		"bad trace id":   {traceparentHeader: "00-zz-0102030405060708-00"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, ok := (w3cPropagator{}).Extract(carrier); ok {
+				t.Errorf("Extract() ok = true, want false")
+			}
+		})
+	}
+}
+
+func TestB3Propagator_MultiHeader(t *testing.T) {
+	carrier := MapCarrier{
+		b3TraceIDHeader: "0102030405060708090a0b0c0d0e0f10",
+		b3SpanIDHeader:  "0102030405060708",
+		b3SampledHeader: "1",
+	}
+	sc, ok := b3Propagator{}.Extract(carrier)
+	if !ok {
+		t.Fatalf("Extract() ok = false, want true")
+	}
+	if !sc.IsSampled() {
+		t.Errorf("IsSampled() = false, want true")
+	}
+
+	injected := MapCarrier{}
+	b3Propagator{}.Inject(sc, injected)
+	if injected[b3TraceIDHeader] != carrier[b3TraceIDHeader] || injected[b3SpanIDHeader] != carrier[b3SpanIDHeader] {
+		t.Errorf("Inject() = %+v, want round-tripped trace/span id", injected)
+	}
+}
+
+func TestB3Propagator_64BitTraceID(t *testing.T) {
+	carrier := MapCarrier{
+		b3TraceIDHeader: "090a0b0c0d0e0f10",
+		b3SpanIDHeader:  "0102030405060708",
+	}
+	sc, ok := b3Propagator{}.Extract(carrier)
+	if !ok {
+		t.Fatalf("Extract() ok = false, want true")
+	}
+	for i := 0; i < 8; i++ {
+		if sc.TraceID[i] != 0 {
+			t.Errorf("TraceID high bytes = %x, want left-padded zeroes", sc.TraceID[:8])
+			break
+		}
+	}
+}
+
+func TestB3Propagator_SingleHeader(t *testing.T) {
+	sc, ok := b3Propagator{}.Extract(MapCarrier{b3SingleHeader: "0102030405060708090a0b0c0d0e0f10-0102030405060708-1"})
+	if !ok {
+		t.Fatalf("Extract() ok = false, want true")
+	}
+	if !sc.IsSampled() {
+		t.Errorf("IsSampled() = false, want true")
+	}
+}
+
+func TestB3Propagator_SingleHeaderUnsampled(t *testing.T) {
+	if _, ok := (b3Propagator{}).Extract(MapCarrier{b3SingleHeader: "0"}); ok {
+		t.Errorf("Extract() ok = true, want false for unsampled-only single header \"0\"")
+	}
+}
+
+func TestLegacyPropagator_RoundTrip(t *testing.T) {
+	sc := SpanContext{TraceOptions: 1}
+	sc.TraceID[0] = 0xAB
+	sc.SpanID[0] = 0xCD
+
+	carrier := MapCarrier{}
+	legacyPropagator{}.Inject(sc, carrier)
+
+	got, ok := legacyPropagator{}.Extract(carrier)
+	if !ok || got.TraceID != sc.TraceID || got.SpanID != sc.SpanID || got.TraceOptions != sc.TraceOptions {
+		t.Errorf("Extract() = %+v, %v, want %+v, true", got, ok, sc)
+	}
+}
+
+func TestCompositePropagator_TriesEachInOrder(t *testing.T) {
+	sc := SpanContext{TraceOptions: 1}
+	sc.TraceID[0] = 1
+	sc.SpanID[0] = 1
+
+	// Only the legacy header is present, so a composite trying W3C and B3 first must still fall
+	// through to it.
+	carrier := MapCarrier{}
+	legacyPropagator{}.Inject(sc, carrier)
+
+	composite := NewCompositePropagator(w3cPropagator{}, b3Propagator{}, legacyPropagator{})
+	got, ok := composite.Extract(carrier)
+	if !ok || got.TraceID != sc.TraceID {
+		t.Errorf("Extract() = %+v, %v, want %+v, true", got, ok, sc)
+	}
+}
+
+func TestCompositePropagator_InjectsAllFormats(t *testing.T) {
+	sc := SpanContext{TraceOptions: 1}
+	carrier := MapCarrier{}
+	NewCompositePropagator(w3cPropagator{}, b3Propagator{}, legacyPropagator{}).Inject(sc, carrier)
+
+	for _, header := range []string{traceparentHeader, b3TraceIDHeader, CorrelationID} {
+		if carrier.Get(header) == "" {
+			t.Errorf("carrier missing %q after Inject", header)
+		}
+	}
+}
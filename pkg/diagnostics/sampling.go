@@ -0,0 +1,147 @@
+package diagnostics
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// SamplingMode selects the head-based sampling strategy a SamplingPolicy applies.
+type SamplingMode int
+
+const (
+	// SamplingAlwaysOn records every span, matching today's default behavior.
+	SamplingAlwaysOn SamplingMode = iota
+	// SamplingNever records no spans.
+	SamplingNever
+	// SamplingProbability records a span with a fixed probability, independent of its parent.
+	SamplingProbability
+	// SamplingRateLimited records at most a fixed number of spans per second, via a token bucket.
+	SamplingRateLimited
+	// SamplingParentBased honors the parent SpanContext's sampled bit whenever there is a parent,
+	// remote or local, and falls through to Fallback when there isn't.
+	SamplingParentBased
+)
+
+// SamplingPolicy configures how head-based sampling decisions are made for new spans.
+type SamplingPolicy struct {
+	Mode SamplingMode
+	// Probability is the sampling rate used when Mode is SamplingProbability, in [0, 1].
+	Probability float64
+	// SpansPerSecond is the token-bucket refill rate used when Mode is SamplingRateLimited.
+	SpansPerSecond float64
+	// Fallback is the policy whose Sampler is used when Mode is SamplingParentBased and there is
+	// no parent to defer to. It defaults to SamplingAlwaysOn if left nil.
+	Fallback *SamplingPolicy
+}
+
+// NewSampler builds the Sampler described by this policy.
+func (p SamplingPolicy) NewSampler() Sampler {
+	switch p.Mode {
+	case SamplingNever:
+		return neverSampler{}
+	case SamplingProbability:
+		return probabilitySampler{probability: p.Probability}
+	case SamplingRateLimited:
+		return &rateLimitedSampler{bucket: newTokenBucket(p.SpansPerSecond)}
+	case SamplingParentBased:
+		fallback := p.Fallback
+		if fallback == nil {
+			fallback = &SamplingPolicy{Mode: SamplingAlwaysOn}
+		}
+		return parentBasedSampler{fallback: fallback.NewSampler()}
+	default:
+		return alwaysOnSampler{}
+	}
+}
+
+// defaultSamplingPolicy is applied by TraceSpanFromCorrelationId, TraceSpanFromContext and
+// TraceSpanFromRoutingContext unless overridden with SetSamplingPolicy.
+var defaultSamplingPolicy = SamplingPolicy{Mode: SamplingAlwaysOn}
+
+// SetSamplingPolicy replaces the package-wide default SamplingPolicy, e.g. from runtime config.
+func SetSamplingPolicy(p SamplingPolicy) {
+	defaultSamplingPolicy = p
+}
+
+type alwaysOnSampler struct{}
+
+func (alwaysOnSampler) ShouldSample(SpanContext) bool { return true }
+
+type neverSampler struct{}
+
+func (neverSampler) ShouldSample(SpanContext) bool { return false }
+
+type probabilitySampler struct {
+	probability float64
+}
+
+func (s probabilitySampler) ShouldSample(SpanContext) bool {
+	return rand.Float64() < s.probability
+}
+
+type parentBasedSampler struct {
+	fallback Sampler
+}
+
+func (s parentBasedSampler) ShouldSample(parent SpanContext) bool {
+	if parent != (SpanContext{}) {
+		return parent.IsSampled()
+	}
+	return s.fallback.ShouldSample(parent)
+}
+
+// rateLimitedSampler admits at most bucket's configured rate of spans per second.
+type rateLimitedSampler struct {
+	bucket *tokenBucket
+}
+
+func (s *rateLimitedSampler) ShouldSample(SpanContext) bool {
+	return s.bucket.Allow()
+}
+
+// tokenBucket is a simple, mutex-guarded token-bucket rate limiter: tokens refill continuously
+// at ratePerSecond up to a capacity of one second's worth, and each Allow call consumes one.
+type tokenBucket struct {
+	mu         sync.Mutex
+	rate       float64
+	tokens     float64
+	capacity   float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(ratePerSecond float64) *tokenBucket {
+	return &tokenBucket{
+		rate:       ratePerSecond,
+		tokens:     ratePerSecond,
+		capacity:   ratePerSecond,
+		lastRefill: time.Now(),
+	}
+}
+
+func (b *tokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.lastRefill = now
+	b.tokens += elapsed * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// StartSpanWithSampler starts a span like activeTracer.StartSpan, but forces the sampling
+// decision for this call site rather than deferring to the Tracer's own sampler, so HTTP, gRPC
+// and internal spans can share the same SamplingPolicy.
+func StartSpanWithSampler(ctx context.Context, name string, sampler Sampler, opts ...SpanOption) (context.Context, Span) {
+	opts = append([]SpanOption{WithSampler(sampler)}, opts...)
+	return activeTracer.StartSpan(ctx, name, opts...)
+}
@@ -0,0 +1,68 @@
+package diagnostics
+
+import "testing"
+
+func TestSamplingPolicy_AlwaysOnAndNever(t *testing.T) {
+	if !(SamplingPolicy{Mode: SamplingAlwaysOn}).NewSampler().ShouldSample(SpanContext{}) {
+		t.Errorf("SamplingAlwaysOn.ShouldSample() = false, want true")
+	}
+	if (SamplingPolicy{Mode: SamplingNever}).NewSampler().ShouldSample(SpanContext{}) {
+		t.Errorf("SamplingNever.ShouldSample() = true, want false")
+	}
+}
+
+func TestSamplingPolicy_Probability(t *testing.T) {
+	if (SamplingPolicy{Mode: SamplingProbability, Probability: 0}).NewSampler().ShouldSample(SpanContext{}) {
+		t.Errorf("Probability 0 sampled, want never")
+	}
+	if !(SamplingPolicy{Mode: SamplingProbability, Probability: 1}).NewSampler().ShouldSample(SpanContext{}) {
+		t.Errorf("Probability 1 not sampled, want always")
+	}
+}
+
+func TestSamplingPolicy_ParentBased(t *testing.T) {
+	sampler := (SamplingPolicy{Mode: SamplingParentBased, Fallback: &SamplingPolicy{Mode: SamplingNever}}).NewSampler()
+
+	sampledParent := SpanContext{TraceOptions: 1}
+	sampledParent.TraceID[0] = 1
+	if !sampler.ShouldSample(sampledParent) {
+		t.Errorf("ShouldSample(sampled parent) = false, want true (honor incoming sampled bit)")
+	}
+
+	unsampledParent := SpanContext{}
+	unsampledParent.TraceID[0] = 1
+	if sampler.ShouldSample(unsampledParent) {
+		t.Errorf("ShouldSample(unsampled parent) = true, want false (honor incoming sampled bit)")
+	}
+
+	if sampler.ShouldSample(SpanContext{}) {
+		t.Errorf("ShouldSample(no parent) = true, want false (fall through to Never fallback)")
+	}
+}
+
+func TestSamplingPolicy_ParentBased_DefaultFallback(t *testing.T) {
+	sampler := (SamplingPolicy{Mode: SamplingParentBased}).NewSampler()
+	if !sampler.ShouldSample(SpanContext{}) {
+		t.Errorf("ShouldSample(no parent) = false, want true (default fallback is SamplingAlwaysOn)")
+	}
+}
+
+func TestTokenBucket_AllowsUpToCapacityThenBlocks(t *testing.T) {
+	b := newTokenBucket(2)
+	if !b.Allow() || !b.Allow() {
+		t.Fatalf("first two Allow() calls should succeed within the initial burst")
+	}
+	if b.Allow() {
+		t.Errorf("Allow() succeeded beyond the token bucket's capacity with no elapsed time")
+	}
+}
+
+func TestRateLimitedSampler_DelegatesToBucket(t *testing.T) {
+	sampler := (SamplingPolicy{Mode: SamplingRateLimited, SpansPerSecond: 1}).NewSampler()
+	if !sampler.ShouldSample(SpanContext{}) {
+		t.Errorf("first ShouldSample() = false, want true within initial burst")
+	}
+	if sampler.ShouldSample(SpanContext{}) {
+		t.Errorf("second immediate ShouldSample() = true, want false once the burst is exhausted")
+	}
+}
@@ -0,0 +1,137 @@
+package diagnostics
+
+import (
+	"context"
+	"time"
+)
+
+// SpanKind identifies the relationship of a span to its parent.
+type SpanKind int
+
+const (
+	// SpanKindUnspecified is the default, used when the caller doesn't know or care whether the
+	// span represents a client or server operation.
+	SpanKindUnspecified SpanKind = iota
+	// SpanKindServer marks a span as handling an inbound request.
+	SpanKindServer
+	// SpanKindClient marks a span as making an outbound request.
+	SpanKindClient
+)
+
+// SpanContext identifies a span across process and backend boundaries, independent of whichever
+// tracing SDK is in use.
+type SpanContext struct {
+	TraceID      [16]byte
+	SpanID       [8]byte
+	TraceOptions uint32
+	// TraceState is the raw W3C tracestate header value, read and written by w3cPropagator. The
+	// OpenCensus and OpenTelemetry Tracer backends do not pass it on: a remote parent's
+	// TraceState is dropped when a span starts, and a started span's SpanContext() has it empty.
+	TraceState string
+}
+
+// IsSampled reports whether the sampled bit is set on this span context.
+func (sc SpanContext) IsSampled() bool {
+	return sc.TraceOptions&1 == 1
+}
+
+// Attribute is a single span or event tag. Value is restricted to string, bool, int64 and
+// float64, the set both OpenCensus and OpenTelemetry accept natively.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// StringAttribute creates a string-valued Attribute.
+func StringAttribute(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// BoolAttribute creates a bool-valued Attribute.
+func BoolAttribute(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int64Attribute creates an int64-valued Attribute.
+func Int64Attribute(key string, value int64) Attribute { return Attribute{Key: key, Value: value} }
+
+// Float64Attribute creates a float64-valued Attribute.
+func Float64Attribute(key string, value float64) Attribute { return Attribute{Key: key, Value: value} }
+
+// Status is the outcome of a span, following the canonical status codes shared by OpenCensus
+// and OpenTelemetry (0 == OK).
+type Status struct {
+	Code    int32
+	Message string
+}
+
+// Sampler decides whether a span with the given (possibly remote) parent context should be
+// recorded. Implementations are provided by pkg/diagnostics' SamplingPolicy.
+type Sampler interface {
+	ShouldSample(parent SpanContext) bool
+}
+
+// spanConfig accumulates the effect of a list of SpanOptions.
+type spanConfig struct {
+	kind         SpanKind
+	attributes   []Attribute
+	remoteParent *SpanContext
+	startTime    time.Time
+	sampler      Sampler
+}
+
+// SpanOption configures a span at start time.
+type SpanOption func(*spanConfig)
+
+// WithSpanKind sets whether the span represents a client or server operation.
+func WithSpanKind(kind SpanKind) SpanOption {
+	return func(c *spanConfig) { c.kind = kind }
+}
+
+// WithAttributes attaches attrs to the span as soon as it is created.
+func WithAttributes(attrs ...Attribute) SpanOption {
+	return func(c *spanConfig) { c.attributes = append(c.attributes, attrs...) }
+}
+
+// WithRemoteParent starts the span as a child of a SpanContext received from another process.
+func WithRemoteParent(sc SpanContext) SpanOption {
+	return func(c *spanConfig) { c.remoteParent = &sc }
+}
+
+// WithStartTime overrides the span's start time, e.g. when reconstructing a span after the fact.
+// The OpenCensus backend (the default activeTracer) has no public API for overriding a span's
+// start time and silently ignores this option; only NewOpenTelemetryTracer honors it.
+func WithStartTime(t time.Time) SpanOption {
+	return func(c *spanConfig) { c.startTime = t }
+}
+
+// WithSampler overrides the Tracer's default sampling decision for this span.
+func WithSampler(s Sampler) SpanOption {
+	return func(c *spanConfig) { c.sampler = s }
+}
+
+// Span is a single unit of work tracked by a Tracer, independent of the tracing backend.
+type Span interface {
+	// AddAttributes attaches key/value tags to the span.
+	AddAttributes(attrs ...Attribute)
+	// Annotate records a timestamped message with attributes, without ending the span.
+	Annotate(attrs []Attribute, msg string)
+	// SetStatus records the span's outcome.
+	SetStatus(status Status)
+	// SpanContext returns the identifiers other spans use to reference this one.
+	SpanContext() SpanContext
+	// End marks the span complete.
+	End()
+}
+
+// Tracer starts spans, abstracting over the underlying tracing SDK (OpenCensus, OpenTelemetry, ...).
+type Tracer interface {
+	StartSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, Span)
+}
+
+// activeTracer is the Tracer used by TraceSpanFromContext and friends. It defaults to the
+// OpenCensus adapter to preserve today's behavior; call SetTracer to switch backends. This is a
+// single process-wide backend, not a per-call or per-component choice: swapping it with
+// SetTracer affects every caller, and tests that need both backends can't run in parallel.
+var activeTracer Tracer = NewOpenCensusTracer()
+
+// SetTracer selects the Tracer backend used package-wide, e.g. from runtime configuration.
+func SetTracer(t Tracer) {
+	activeTracer = t
+}
@@ -0,0 +1,191 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// chromeTraceEvent is one entry in the Chrome/Perfetto trace-event JSON format: phases "B"/"E"
+// bracket a span's lifetime, "s"/"f" mark the two ends of a flow arrow, both keyed by a shared
+// id, following the phase vocabulary cmd/go/internal/trace uses for goroutine execution traces.
+type chromeTraceEvent struct {
+	Name string                 `json:"name,omitempty"`
+	Cat  string                 `json:"cat"`
+	Ph   string                 `json:"ph"`
+	Ts   int64                  `json:"ts"`
+	Pid  int                    `json:"pid"`
+	Tid  uint64                 `json:"tid"`
+	ID   string                 `json:"id,omitempty"`
+	BP   string                 `json:"bp,omitempty"`
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+// ChromeTraceExporter writes span begin/end and cross-process flow events to a JSON file that
+// chrome://tracing or Perfetto can load directly, so a Dapr request graph can be inspected
+// locally without standing up a Jaeger/Zipkin backend.
+type ChromeTraceExporter struct {
+	mu    sync.Mutex
+	f     *os.File
+	start time.Time
+	pid   int
+}
+
+// NewChromeTraceExporter creates (truncating if it exists) the trace file at path and returns
+// an exporter ready to be wrapped around a Tracer with NewChromeTraceTracer. Call Close when
+// done tracing to finish the JSON array.
+func NewChromeTraceExporter(path string) (*ChromeTraceExporter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.WriteString("[\n"); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &ChromeTraceExporter{f: f, start: time.Now(), pid: os.Getpid()}, nil
+}
+
+// Close finishes the JSON array and closes the underlying file. The trailing "{}" absorbs the
+// dangling comma after the last event so the file parses as valid JSON.
+func (e *ChromeTraceExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err := e.f.WriteString("{}]\n")
+	if closeErr := e.f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// micros returns t as microseconds elapsed since the exporter was created, the timestamp unit
+// the trace-viewer format expects.
+func (e *ChromeTraceExporter) micros(t time.Time) int64 {
+	return t.Sub(e.start).Microseconds()
+}
+
+func (e *ChromeTraceExporter) writeEvent(ev chromeTraceEvent) {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.f.Write(b)
+	e.f.WriteString(",\n")
+}
+
+// tidForSpan derives a stable per-span trace-viewer thread id from the span's SpanID. Go spans
+// aren't tied to a single OS thread or goroutine, so each span is given its own synthetic row
+// rather than trying to recover a real thread id. The result is masked to 53 bits, the largest
+// integer magnitude JSON consumers implemented with JS floats (chrome://tracing, Perfetto) can
+// represent exactly, so the displayed row id is exactly the value written. SpanIDs that differ
+// only in their top 11 bits still share a row.
+func tidForSpan(sc SpanContext) uint64 {
+	const jsSafeIntegerMask = 1<<53 - 1
+	return binary.BigEndian.Uint64(sc.SpanID[:]) & jsSafeIntegerMask
+}
+
+// chromeTraceTracer wraps another Tracer, emitting a begin/end event pair per span - and a
+// flow-start/flow-end pair across a client/server boundary - to a ChromeTraceExporter, while
+// delegating the actual span behavior unchanged.
+type chromeTraceTracer struct {
+	delegate Tracer
+	exporter *ChromeTraceExporter
+}
+
+// NewChromeTraceTracer returns a Tracer that records every span delegate starts into exporter
+// as well, for local profiling alongside a real backend. No command-line flag installs it; the
+// returned Tracer takes effect only once passed to SetTracer.
+func NewChromeTraceTracer(delegate Tracer, exporter *ChromeTraceExporter) Tracer {
+	return &chromeTraceTracer{delegate: delegate, exporter: exporter}
+}
+
+func (t *chromeTraceTracer) StartSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, Span) {
+	newCtx, span := t.delegate.StartSpan(ctx, name, opts...)
+
+	cfg := spanConfig{}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	sc := span.SpanContext()
+	tid := tidForSpan(sc)
+	ts := t.exporter.micros(time.Now())
+	t.exporter.writeEvent(chromeTraceEvent{
+		Name: name,
+		Cat:  "span",
+		Ph:   "B",
+		Ts:   ts,
+		Pid:  t.exporter.pid,
+		Tid:  tid,
+		Args: map[string]interface{}{
+			"traceId": traceIDHex(sc),
+			"spanId":  spanIDHex(sc),
+		},
+	})
+
+	// A client span kicks off a call to another sidecar; a span with a remote parent is the
+	// other end picking it up. The client span's own SpanID is exactly what gets propagated on
+	// the wire as the callee's remote-parent SpanID, so it - not the trace-wide TraceID, which
+	// every span in the whole request graph shares - uniquely identifies this one hop and is
+	// what flow-start/flow-end are paired on.
+	if cfg.kind == SpanKindClient {
+		t.exporter.writeEvent(chromeTraceEvent{
+			Name: "cross-sidecar call",
+			Cat:  "flow",
+			Ph:   "s",
+			Ts:   ts,
+			Pid:  t.exporter.pid,
+			Tid:  tid,
+			ID:   spanIDHex(sc),
+		})
+	}
+	if cfg.remoteParent != nil {
+		t.exporter.writeEvent(chromeTraceEvent{
+			Name: "cross-sidecar call",
+			Cat:  "flow",
+			Ph:   "f",
+			Ts:   ts,
+			Pid:  t.exporter.pid,
+			Tid:  tid,
+			ID:   spanIDHex(*cfg.remoteParent),
+			BP:   "e",
+		})
+	}
+
+	return newCtx, &chromeTraceSpan{Span: span, exporter: t.exporter, name: name, tid: tid}
+}
+
+// chromeTraceSpan wraps a Span, emitting the matching "E" phase event to the exporter on End
+// and otherwise delegating to the wrapped Span unchanged.
+type chromeTraceSpan struct {
+	Span
+	exporter *ChromeTraceExporter
+	name     string
+	tid      uint64
+}
+
+func (s *chromeTraceSpan) End() {
+	s.exporter.writeEvent(chromeTraceEvent{
+		Name: s.name,
+		Cat:  "span",
+		Ph:   "E",
+		Ts:   s.exporter.micros(time.Now()),
+		Pid:  s.exporter.pid,
+		Tid:  s.tid,
+	})
+	s.Span.End()
+}
+
+func traceIDHex(sc SpanContext) string {
+	return hex.EncodeToString(sc.TraceID[:])
+}
+
+func spanIDHex(sc SpanContext) string {
+	return hex.EncodeToString(sc.SpanID[:])
+}
@@ -0,0 +1,114 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeTracer/fakeSpan stand in for a real backend so chromeTraceTracer's own event emission can
+// be tested without OpenCensus or OpenTelemetry.
+type fakeTracer struct{ nextSpanID byte }
+
+func (t *fakeTracer) StartSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, Span) {
+	t.nextSpanID++
+	sc := SpanContext{}
+	sc.TraceID[0] = 1
+	sc.SpanID[0] = t.nextSpanID
+	return ctx, &fakeSpan{sc: sc}
+}
+
+type fakeSpan struct {
+	sc   SpanContext
+	ends int
+}
+
+func (s *fakeSpan) AddAttributes(attrs ...Attribute) {}
+func (s *fakeSpan) Annotate(attrs []Attribute, msg string) {}
+func (s *fakeSpan) SetStatus(status Status) {}
+func (s *fakeSpan) SpanContext() SpanContext { return s.sc }
+func (s *fakeSpan) End() { s.ends++ }
+
+func readTraceEvents(t *testing.T, path string) []chromeTraceEvent {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%q): %v", path, err)
+	}
+	var events []chromeTraceEvent
+	if err := json.Unmarshal(data, &events); err != nil {
+		t.Fatalf("file is not valid JSON: %v\ncontent: %s", err, data)
+	}
+	return events
+}
+
+func TestChromeTraceTracer_EmitsBeginAndEnd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.json")
+	exporter, err := NewChromeTraceExporter(path)
+	if err != nil {
+		t.Fatalf("NewChromeTraceExporter: %v", err)
+	}
+
+	tracer := NewChromeTraceTracer(&fakeTracer{}, exporter)
+	_, span := tracer.StartSpan(context.Background(), "op")
+	span.End()
+
+	if err := exporter.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	events := readTraceEvents(t, path)
+	var begins, ends int
+	for _, e := range events {
+		switch e.Ph {
+		case "B":
+			begins++
+		case "E":
+			ends++
+		}
+	}
+	if begins != 1 || ends != 1 {
+		t.Errorf("begins=%d ends=%d, want 1 and 1", begins, ends)
+	}
+}
+
+func TestChromeTraceTracer_PairsFlowEventsBySpanID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.json")
+	exporter, err := NewChromeTraceExporter(path)
+	if err != nil {
+		t.Fatalf("NewChromeTraceExporter: %v", err)
+	}
+
+	tracer := NewChromeTraceTracer(&fakeTracer{}, exporter)
+	_, clientSpan := tracer.StartSpan(context.Background(), "call-out", WithSpanKind(SpanKindClient))
+	clientSC := clientSpan.SpanContext()
+
+	_, serverSpan := tracer.StartSpan(context.Background(), "call-in", WithRemoteParent(clientSC))
+
+	if err := exporter.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	_ = serverSpan
+
+	events := readTraceEvents(t, path)
+	var startID, endID string
+	for _, e := range events {
+		switch e.Ph {
+		case "s":
+			startID = e.ID
+		case "f":
+			endID = e.ID
+		}
+	}
+	if startID == "" || endID == "" {
+		t.Fatalf("expected both a flow-start and flow-end event, got start=%q end=%q", startID, endID)
+	}
+	if startID != endID {
+		t.Errorf("flow-start id = %q, flow-end id = %q, want them to match (paired on the hop-specific SpanID)", startID, endID)
+	}
+	if startID == traceIDHex(clientSC) {
+		t.Errorf("flow id = trace-wide TraceID %q; it must be the hop-specific SpanID instead, or fan-out calls sharing a TraceID collide", startID)
+	}
+}
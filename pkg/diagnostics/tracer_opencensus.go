@@ -0,0 +1,116 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+
+	"go.opencensus.io/trace"
+)
+
+// ocTracer is the default Tracer, backed by go.opencensus.io/trace so existing Dapr deployments
+// keep their current tracing behavior unchanged.
+type ocTracer struct{}
+
+// NewOpenCensusTracer returns a Tracer backed by OpenCensus.
+func NewOpenCensusTracer() Tracer {
+	return ocTracer{}
+}
+
+func (ocTracer) StartSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, Span) {
+	cfg := spanConfig{}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	var startOpts []trace.StartOption
+	switch cfg.kind {
+	case SpanKindServer:
+		startOpts = append(startOpts, trace.WithSpanKind(trace.SpanKindServer))
+	case SpanKindClient:
+		startOpts = append(startOpts, trace.WithSpanKind(trace.SpanKindClient))
+	}
+	if cfg.sampler != nil {
+		startOpts = append(startOpts, trace.WithSampler(ocSamplerFunc(cfg.sampler)))
+	}
+
+	var newCtx context.Context
+	var span *trace.Span
+	if cfg.remoteParent != nil {
+		newCtx, span = trace.StartSpanWithRemoteParent(ctx, name, toOCSpanContext(*cfg.remoteParent), startOpts...)
+	} else {
+		newCtx, span = trace.StartSpan(ctx, name, startOpts...)
+	}
+	if len(cfg.attributes) > 0 {
+		span.AddAttributes(toOCAttributes(cfg.attributes)...)
+	}
+	return newCtx, &ocSpan{span}
+}
+
+// ocSpan adapts *trace.Span to the Span interface.
+type ocSpan struct {
+	span *trace.Span
+}
+
+func (s *ocSpan) AddAttributes(attrs ...Attribute) {
+	s.span.AddAttributes(toOCAttributes(attrs)...)
+}
+
+func (s *ocSpan) Annotate(attrs []Attribute, msg string) {
+	s.span.Annotate(toOCAttributes(attrs), msg)
+}
+
+func (s *ocSpan) SetStatus(status Status) {
+	s.span.SetStatus(trace.Status{Code: status.Code, Message: status.Message})
+}
+
+func (s *ocSpan) SpanContext() SpanContext {
+	return fromOCSpanContext(s.span.SpanContext())
+}
+
+func (s *ocSpan) End() {
+	s.span.End()
+}
+
+// ocSamplerFunc adapts a Sampler to OpenCensus' trace.Sampler function type.
+func ocSamplerFunc(s Sampler) trace.Sampler {
+	return func(p trace.SamplingParameters) trace.SamplingDecision {
+		return trace.SamplingDecision{Sample: s.ShouldSample(fromOCSpanContext(p.ParentContext))}
+	}
+}
+
+func toOCSpanContext(sc SpanContext) trace.SpanContext {
+	ret := trace.SpanContext{TraceOptions: trace.TraceOptions(sc.TraceOptions)}
+	ret.TraceID = trace.TraceID(sc.TraceID)
+	ret.SpanID = trace.SpanID(sc.SpanID)
+	return ret
+}
+
+func fromOCSpanContext(sc trace.SpanContext) SpanContext {
+	return SpanContext{
+		TraceID:      [16]byte(sc.TraceID),
+		SpanID:       [8]byte(sc.SpanID),
+		TraceOptions: uint32(sc.TraceOptions),
+	}
+}
+
+func toOCAttributes(attrs []Attribute) []trace.Attribute {
+	if len(attrs) == 0 {
+		return nil
+	}
+	ret := make([]trace.Attribute, 0, len(attrs))
+	for _, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			ret = append(ret, trace.StringAttribute(a.Key, v))
+		case bool:
+			ret = append(ret, trace.BoolAttribute(a.Key, v))
+		case int64:
+			ret = append(ret, trace.Int64Attribute(a.Key, v))
+		case float64:
+			ret = append(ret, trace.Float64Attribute(a.Key, v))
+		default:
+			ret = append(ret, trace.StringAttribute(a.Key, fmt.Sprintf("%v", v)))
+		}
+	}
+	return ret
+}
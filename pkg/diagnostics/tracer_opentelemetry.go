@@ -0,0 +1,174 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies this package to the OpenTelemetry SDK.
+const instrumentationName = "github.com/dapr/dapr/pkg/diagnostics"
+
+// otelTracer is a Tracer backed by the OpenTelemetry SDK, for operators who want to export to
+// an OTel collector instead of via OpenCensus.
+type otelTracer struct {
+	tracer oteltrace.Tracer
+}
+
+// NewOpenTelemetryTracer returns a Tracer backed by the global OpenTelemetry TracerProvider. See
+// OpenTelemetrySampler for what that provider needs to honor SamplingPolicy.
+func NewOpenTelemetryTracer() Tracer {
+	return &otelTracer{tracer: otel.Tracer(instrumentationName)}
+}
+
+func (t *otelTracer) StartSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, Span) {
+	cfg := spanConfig{}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	if cfg.remoteParent != nil {
+		ctx = oteltrace.ContextWithRemoteSpanContext(ctx, toOTelSpanContext(*cfg.remoteParent))
+	}
+
+	var startOpts []oteltrace.SpanStartOption
+	switch cfg.kind {
+	case SpanKindServer:
+		startOpts = append(startOpts, oteltrace.WithSpanKind(oteltrace.SpanKindServer))
+	case SpanKindClient:
+		startOpts = append(startOpts, oteltrace.WithSpanKind(oteltrace.SpanKindClient))
+	}
+	if !cfg.startTime.IsZero() {
+		startOpts = append(startOpts, oteltrace.WithTimestamp(cfg.startTime))
+	}
+	if len(cfg.attributes) > 0 {
+		startOpts = append(startOpts, oteltrace.WithAttributes(toOTelAttributes(cfg.attributes)...))
+	}
+	// The OTel SDK makes sampling a TracerProvider-level concern rather than a per-span one, so
+	// cfg.sampler is smuggled in via the context instead: OpenTelemetrySampler, registered on
+	// the TracerProvider backing this Tracer, reads it back out of SamplingParameters.ParentContext.
+	if cfg.sampler != nil {
+		ctx = context.WithValue(ctx, otelSamplerContextKey{}, cfg.sampler)
+	}
+
+	newCtx, span := t.tracer.Start(ctx, name, startOpts...)
+	return newCtx, &otelSpan{span}
+}
+
+// otelSpan adapts oteltrace.Span to the Span interface.
+type otelSpan struct {
+	span oteltrace.Span
+}
+
+func (s *otelSpan) AddAttributes(attrs ...Attribute) {
+	s.span.SetAttributes(toOTelAttributes(attrs)...)
+}
+
+func (s *otelSpan) Annotate(attrs []Attribute, msg string) {
+	s.span.AddEvent(msg, oteltrace.WithAttributes(toOTelAttributes(attrs)...))
+}
+
+func (s *otelSpan) SetStatus(status Status) {
+	code := codes.Ok
+	if status.Code != 0 {
+		code = codes.Error
+	}
+	s.span.SetStatus(code, status.Message)
+}
+
+func (s *otelSpan) SpanContext() SpanContext {
+	return fromOTelSpanContext(s.span.SpanContext())
+}
+
+func (s *otelSpan) End() {
+	s.span.End()
+}
+
+func toOTelSpanContext(sc SpanContext) oteltrace.SpanContext {
+	var flags oteltrace.TraceFlags
+	if sc.IsSampled() {
+		flags = oteltrace.FlagsSampled
+	}
+	return oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    oteltrace.TraceID(sc.TraceID),
+		SpanID:     oteltrace.SpanID(sc.SpanID),
+		TraceFlags: flags,
+		Remote:     true,
+	})
+}
+
+func fromOTelSpanContext(sc oteltrace.SpanContext) SpanContext {
+	ret := SpanContext{
+		TraceID: [16]byte(sc.TraceID()),
+		SpanID:  [8]byte(sc.SpanID()),
+	}
+	if sc.IsSampled() {
+		ret.TraceOptions = 1
+	}
+	return ret
+}
+
+// otelSamplerContextKey is the context key otelTracer.StartSpan uses to pass the per-call
+// Sampler through to OpenTelemetrySampler.
+type otelSamplerContextKey struct{}
+
+// OpenTelemetrySampler adapts the package's SamplingPolicy into an OpenTelemetry SDK Sampler.
+// Operators building their own TracerProvider for use with NewOpenTelemetryTracer must register
+// it (sdktrace.WithSampler(diagnostics.OpenTelemetrySampler())) for SamplingPolicy to take
+// effect under the OTel backend; without it, the TracerProvider's own configured Sampler decides
+// instead, and every call site using StartSpanWithSampler is silently ignored.
+func OpenTelemetrySampler() sdktrace.Sampler {
+	return otelSamplerAdapter{}
+}
+
+// otelSamplerAdapter implements sdktrace.Sampler by delegating to the Sampler stashed on the
+// span's parent context by otelTracer.StartSpan.
+type otelSamplerAdapter struct{}
+
+func (otelSamplerAdapter) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	decision := sdktrace.RecordAndSample
+	if sampler, ok := p.ParentContext.Value(otelSamplerContextKey{}).(Sampler); ok {
+		var parent SpanContext
+		if psc := oteltrace.SpanContextFromContext(p.ParentContext); psc.IsValid() {
+			parent = fromOTelSpanContext(psc)
+		}
+		if !sampler.ShouldSample(parent) {
+			decision = sdktrace.Drop
+		}
+	}
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: oteltrace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+func (otelSamplerAdapter) Description() string {
+	return "diagnostics.Sampler adapter"
+}
+
+func toOTelAttributes(attrs []Attribute) []attribute.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	ret := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			ret = append(ret, attribute.String(a.Key, v))
+		case bool:
+			ret = append(ret, attribute.Bool(a.Key, v))
+		case int64:
+			ret = append(ret, attribute.Int64(a.Key, v))
+		case float64:
+			ret = append(ret, attribute.Float64(a.Key, v))
+		default:
+			ret = append(ret, attribute.String(a.Key, fmt.Sprintf("%v", v)))
+		}
+	}
+	return ret
+}
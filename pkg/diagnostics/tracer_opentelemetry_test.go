@@ -0,0 +1,36 @@
+package diagnostics
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type fakeSampler struct{ sample bool }
+
+func (f fakeSampler) ShouldSample(SpanContext) bool { return f.sample }
+
+func TestOpenTelemetrySampler_HonorsContextSampler(t *testing.T) {
+	tests := []struct {
+		name     string
+		sampler  Sampler
+		wantDrop bool
+	}{
+		{"no sampler stashed on context samples by default", nil, false},
+		{"sampler says sample", fakeSampler{sample: true}, false},
+		{"sampler says drop", fakeSampler{sample: false}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.sampler != nil {
+				ctx = context.WithValue(ctx, otelSamplerContextKey{}, tc.sampler)
+			}
+			result := OpenTelemetrySampler().ShouldSample(sdktrace.SamplingParameters{ParentContext: ctx})
+			if gotDrop := result.Decision == sdktrace.Drop; gotDrop != tc.wantDrop {
+				t.Errorf("ShouldSample() decision = %v, want drop=%v", result.Decision, tc.wantDrop)
+			}
+		})
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"time"
 
 	routing "github.com/qiangxue/fasthttp-routing"
-	"go.opencensus.io/trace"
 )
 
 const (
@@ -34,86 +33,90 @@ type KeyValState struct {
 }
 
 //SerializeSpanContext seralizes a span context into a simple string
-func SerializeSpanContext(ctx trace.SpanContext) string {
-	return fmt.Sprintf("%s;%s;%d", ctx.SpanID.String(), ctx.TraceID.String(), ctx.TraceOptions)
+func SerializeSpanContext(ctx SpanContext) string {
+	return fmt.Sprintf("%s;%s;%d", hex.EncodeToString(ctx.SpanID[:]), hex.EncodeToString(ctx.TraceID[:]), ctx.TraceOptions)
 }
 
 //DeserializeSpanContext deseralizes a span cotnext from a string
-func DeserializeSpanContext(ctx string) trace.SpanContext {
+func DeserializeSpanContext(ctx string) SpanContext {
 	parts := strings.Split(ctx, ";")
 	spanID, _ := hex.DecodeString(parts[0])
 	traceID, _ := hex.DecodeString(parts[1])
 	traceOptions, _ := strconv.ParseUint(parts[2], 10, 32)
-	ret := trace.SpanContext{}
+	ret := SpanContext{}
 	copy(ret.SpanID[:], spanID[:])
 	copy(ret.TraceID[:], traceID[:])
-	ret.TraceOptions = trace.TraceOptions(traceOptions)
+	ret.TraceOptions = uint32(traceOptions)
 	return ret
 }
 
 // DeserializeSpanContextPointer deseralizes a span context from a trace pointer
-func DeserializeSpanContextPointer(ctx string) *trace.SpanContext {
+func DeserializeSpanContextPointer(ctx string) *SpanContext {
 	if ctx == "" {
 		return nil
 	}
-	var context *trace.SpanContext = &trace.SpanContext{}
+	var context *SpanContext = &SpanContext{}
 	*context = DeserializeSpanContext(ctx)
 	return context
 }
 
-// TraceSpanFromCorrelationId traces a span from a given correlation id
-func TraceSpanFromCorrelationId(corID string, operation string, actionMethod string, targetID string, from string, verbMethod string) (context.Context, *trace.Span) {
-	var ctx context.Context
-	var span *trace.Span
-	if corID != "" {
-		spanContext := DeserializeSpanContext(corID)
-		ctx, span = trace.StartSpanWithRemoteParent(context.Background(), operation, spanContext)
-	} else {
-		ctx, span = trace.StartSpan(context.Background(), operation)
+// TraceSpanFromCorrelationId traces a span from a given gRPC entry point. The remote parent, if
+// any, is extracted using defaultPropagator, so W3C traceparent, B3 and the legacy
+// correlation-id metadata key are all understood.
+func TraceSpanFromCorrelationId(md map[string][]string, operation string, actionMethod string, targetID string, from string, verbMethod string) (context.Context, Span) {
+	var opts []SpanOption
+	if md != nil {
+		if spanContext, ok := defaultPropagator.Extract(NewMetadataCarrier(md)); ok {
+			opts = append(opts, WithRemoteParent(spanContext))
+		}
 	}
-	attrs := []trace.Attribute{
-		trace.StringAttribute("actionMethod", actionMethod),
-		trace.StringAttribute("targetID", targetID),
-		trace.StringAttribute("from", from),
-		trace.StringAttribute("verbMethod", verbMethod),
+	ctx, span := StartSpanWithSampler(context.Background(), operation, defaultSamplingPolicy.NewSampler(), opts...)
+	attrs := []Attribute{
+		StringAttribute("actionMethod", actionMethod),
+		StringAttribute("targetID", targetID),
+		StringAttribute("from", from),
+		StringAttribute("verbMethod", verbMethod),
 	}
 	span.Annotate(attrs, "actionCall")
 	span.AddAttributes(attrs...)
 	return ctx, span
 }
 
-// TraceSpanFromContext starts a span and traces a context with the given params
-func TraceSpanFromContext(c context.Context, events *[]Event, operation string, includeEvent bool, includeEventBody bool) (context.Context, *trace.Span, *trace.SpanContext) {
-	ctx, span := trace.StartSpan(c, operation)
+// TraceSpanFromContext starts a span and traces a context with the given params. Any Baggage
+// already carried by c is attached to the new span as attributes.
+func TraceSpanFromContext(c context.Context, events *[]Event, operation string, includeEvent bool, includeEventBody bool) (context.Context, Span, *SpanContext) {
+	ctx, span := StartSpanWithSampler(c, operation, defaultSamplingPolicy.NewSampler())
+	span.AddAttributes(baggageAttributes(BaggageFromContext(c))...)
 	if includeEvent {
 		AddEventAnnotations(events, span, includeEventBody)
 	}
-	var context *trace.SpanContext = &trace.SpanContext{}
+	var context *SpanContext = &SpanContext{}
 	*context = span.SpanContext()
 	return ctx, span, context
 }
 
-// TraceSpanFromRoutingContext starts a span and traces a context from a given http route context
-func TraceSpanFromRoutingContext(c *routing.Context, events *[]Event, operation string, includeEvent bool, includeEventBody bool) (context.Context, *trace.Span, *trace.SpanContext) {
-	var ctx context.Context
-	var span *trace.Span
-	if c == nil {
-		ctx, span = trace.StartSpan(context.Background(), operation)
-	} else {
-		corID := string(c.Request.Header.Peek(CorrelationID))
-		if corID != "" {
-			spanContext := DeserializeSpanContext(corID)
-			ctx, span = trace.StartSpanWithRemoteParent(context.Background(), operation, spanContext)
-		} else {
-			ctx, span = trace.StartSpan(context.Background(), operation)
+// TraceSpanFromRoutingContext starts a span and traces a context from a given http route context.
+// The remote parent, if any, is extracted using defaultPropagator, so W3C traceparent, B3 and the
+// legacy correlation-id header are all understood.
+func TraceSpanFromRoutingContext(c *routing.Context, events *[]Event, operation string, includeEvent bool, includeEventBody bool) (context.Context, Span, *SpanContext) {
+	base := context.Background()
+	var opts []SpanOption
+	if c != nil {
+		for k, v := range ExtractBaggage(routingContextCarrier{c}) {
+			base = WithBaggage(base, k, v)
+		}
+		if spanContext, ok := defaultPropagator.Extract(routingContextCarrier{c}); ok {
+			opts = append(opts, WithRemoteParent(spanContext))
 		}
 	}
+	ctx, span := StartSpanWithSampler(base, operation, defaultSamplingPolicy.NewSampler(), opts...)
+	span.AddAttributes(baggageAttributes(BaggageFromContext(ctx))...)
 	if includeEvent {
 		AddEventAnnotations(events, span, includeEventBody)
 	}
-	var context *trace.SpanContext
+	var context *SpanContext
 	if span != nil {
-		context = &trace.SpanContext{}
+		context = &SpanContext{}
 		*context = span.SpanContext()
 		return ctx, span, context
 	} else {
@@ -121,29 +124,38 @@ func TraceSpanFromRoutingContext(c *routing.Context, events *[]Event, operation
 	}
 }
 
-// AddEventAnnotations adds an Actions events annotation
-func AddEventAnnotations(events *[]Event, span *trace.Span, includeEventBody bool) {
+// AddEventAnnotations adds an Actions events annotation. Each state key's value and, when
+// includeEventBody is set, the event's data payload are walked into one attribute per leaf
+// (see walkAttributes) rather than collapsed into a single stringified blob, so they stay
+// inspectable as individual fields in the tracing backend's UI.
+func AddEventAnnotations(events *[]Event, span Span, includeEventBody bool) {
 	for _, e := range *events {
-		attrs := []trace.Attribute{
-			trace.StringAttribute("eventName", e.EventName),
-			trace.StringAttribute("createdAt", e.CreatedAt.String()),
-			trace.StringAttribute("concurrency", e.Concurrency),
-			trace.StringAttribute("to", strings.Join(e.To, ",")),
+		attrs := []Attribute{
+			StringAttribute("eventName", e.EventName),
+			StringAttribute("createdAt", e.CreatedAt.String()),
+			StringAttribute("concurrency", e.Concurrency),
+			StringAttribute("to", strings.Join(e.To, ",")),
+		}
+		budget := newAttributeBudget()
+		for _, s := range e.State {
+			attrs = append(attrs, walkAttributes("state."+s.Key, s.Value, budget)...)
 		}
 		span.Annotate(attrs, "message")
 		if includeEventBody {
-			attrs = append(attrs, trace.StringAttribute("data", fmt.Sprintf("%v", e.Data)))
+			dataAttrs := walkAttributes("data", e.Data, budget)
+			span.Annotate(dataAttrs, "data")
+			attrs = append(attrs, dataAttrs...)
 		}
 		span.AddAttributes(attrs...)
 	}
 }
 
 // SetSpanStatus sets the status for a given span
-func SetSpanStatus(span *trace.Span, code int32, message string) {
+func SetSpanStatus(span Span, code int32, message string) {
 	if span != nil {
-		span.SetStatus(trace.Status{
+		span.SetStatus(Status{
 			Code:    code,
 			Message: message,
 		})
 	}
-}
\ No newline at end of file
+}